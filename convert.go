@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/KunalSin9h/memgc/binfmt"
+)
+
+// runConvert implements the "memgc convert" subcommand: it decodes a
+// --format=bin capture and re-encodes it as csv, json, or parquet.
+func runConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	in := fs.String("in", "", "Path to a --format=bin capture to decode.")
+	out := fs.String("out", "", "Output path for the converted file.")
+	outFormat := fs.String("to", "csv", "Output format: csv, json, or parquet.")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		fmt.Fprintln(os.Stderr, "usage: memgc convert -in=capture.bin -out=data.csv -to=csv")
+		os.Exit(1)
+	}
+
+	inFile, err := os.Open(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening capture: %v\n", err)
+		os.Exit(1)
+	}
+	defer inFile.Close()
+
+	reader, err := binfmt.NewReader(inFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading capture: %v\n", err)
+		os.Exit(1)
+	}
+	defer reader.Close()
+
+	outFile, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+		os.Exit(1)
+	}
+	defer outFile.Close()
+
+	switch *outFormat {
+	case "csv":
+		err = convertToCSV(reader, outFile)
+	case "json":
+		err = convertToJSON(reader, outFile)
+	case "parquet":
+		err = convertToParquet(reader, outFile)
+	default:
+		err = fmt.Errorf("unknown -to format %q (want csv, json, or parquet)", *outFormat)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error converting capture: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func convertToCSV(r *binfmt.Reader, w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(r.Header.Columns); err != nil {
+		return err
+	}
+	for {
+		row, err := r.ReadRow()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func convertToJSON(r *binfmt.Reader, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	columns := r.Header.Columns
+	for {
+		row, err := r.ReadRow()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		record := make(map[string]string, len(columns))
+		for i, col := range columns {
+			if i < len(row) {
+				record[col] = row[i]
+			}
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertToParquet(r *binfmt.Reader, w io.Writer) error {
+	columns := r.Header.Columns
+	group := make(parquet.Group, len(columns))
+	for _, col := range columns {
+		group[col] = parquet.String()
+	}
+	schema := parquet.NewSchema("row", group)
+	pw := parquet.NewWriter(w, schema)
+
+	for {
+		row, err := r.ReadRow()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		record := make(map[string]string, len(columns))
+		for i, col := range columns {
+			if i < len(row) {
+				record[col] = row[i]
+			}
+		}
+		if err := pw.Write(record); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+)
+
+// csvRecord is satisfied by any parsed event that can render itself as
+// a CSV row. GCTrace and exectrace.Event both implement it, so the
+// gctrace and --exec-trace output modes share this one writer path
+// instead of duplicating the header-once/flush/sync discipline.
+type csvRecord interface {
+	ToCSVHeader() []string
+	ToCSVRow() []string
+}
+
+// writeCSVRecord writes rec to w, writing the header first if this is
+// the first record seen (headerWritten is updated in place), then
+// flushes and, if durable is set, fsyncs f before returning.
+func writeCSVRecord(w *csv.Writer, f *os.File, rec csvRecord, headerWritten *bool, durable bool) error {
+	if !*headerWritten {
+		if err := w.Write(rec.ToCSVHeader()); err != nil {
+			return err
+		}
+		*headerWritten = true
+	}
+
+	if err := w.Write(rec.ToCSVRow()); err != nil {
+		return err
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	if durable {
+		return f.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package dashboard serves a small live web dashboard for a stream of
+// GC events: an embedded HTML/JS page that renders time-series charts
+// over Server-Sent Events, plus a /metrics endpoint in Prometheus
+// exposition format for the same series.
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync"
+)
+
+//go:embed static/index.html static/app.js
+var staticFiles embed.FS
+
+// Event is one GC event's measurements, as pushed to the dashboard.
+type Event struct {
+	GCNum      int     `json:"gcNum"`
+	Timestamp  float64 `json:"timestamp"`
+	PauseMs    float64 `json:"pauseMs"`
+	HeapInUse  int     `json:"heapInUse"`
+	HeapGoal   int     `json:"heapGoal"`
+	CPUPercent float64 `json:"cpuPercent"`
+	GCHz       float64 `json:"gcHz"`
+}
+
+// Server broadcasts Events to any number of connected browsers over
+// Server-Sent Events, and mirrors the latest values at /metrics for
+// Prometheus to scrape.
+//
+// Server is safe for concurrent use.
+type Server struct {
+	mu      sync.Mutex
+	clients map[chan Event]struct{}
+	latest  Event
+	seen    bool
+}
+
+// NewServer returns a Server with no connected clients yet.
+func NewServer() *Server {
+	return &Server{clients: make(map[chan Event]struct{})}
+}
+
+// Broadcast pushes ev to every currently connected browser and updates
+// the snapshot served at /metrics.
+func (s *Server) Broadcast(ev Event) {
+	s.mu.Lock()
+	s.latest = ev
+	s.seen = true
+	for ch := range s.clients {
+		select {
+		case ch <- ev:
+		default:
+			// Slow client: drop the event rather than block the trace loop.
+		}
+	}
+	s.mu.Unlock()
+}
+
+// Handler returns the http.Handler serving the dashboard page, its
+// static assets, the /events SSE stream, and the /metrics endpoint.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		// staticFiles is embedded at build time, so this can't fail.
+		panic(err)
+	}
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	return mux
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := make(chan Event, 16)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case ev := <-ch:
+			payload, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ev, seen := s.latest, s.seen
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if !seen {
+		return
+	}
+
+	metric := func(name, help, typ string, value float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, typ, name, value)
+	}
+
+	metric("memgc_gc_number", "The GC cycle number of the most recent event.", "counter", float64(ev.GCNum))
+	metric("memgc_pause_ms", "Total stop-the-world pause of the most recent GC, in ms.", "gauge", ev.PauseMs)
+	metric("memgc_heap_inuse_mb", "Heap in-use after the most recent GC, in MB.", "gauge", float64(ev.HeapInUse))
+	metric("memgc_heap_goal_mb", "Heap goal for the most recent GC, in MB.", "gauge", float64(ev.HeapGoal))
+	metric("memgc_cpu_percent", "CPU percent spent in GC as of the most recent event.", "gauge", ev.CPUPercent)
+	metric("memgc_gc_frequency_hz", "Instantaneous GC frequency between the last two events, in Hz.", "gauge", ev.GCHz)
+}
@@ -0,0 +1,63 @@
+// Package exectrace parses binary runtime/trace files (produced by
+// runtime/trace.Start / go tool trace) and reduces them to the handful
+// of GC-relevant events gctrace aggregates away: individual GC, STW,
+// mark-assist, and sweep ranges, with per-goroutine timing.
+//
+// Parsing is done with golang.org/x/exp/trace, which only understands
+// the trace format emitted by Go 1.22 and later; traces produced by an
+// older runtime/trace.Start are rejected with an "unsupported trace
+// version" error.
+package exectrace
+
+import "strconv"
+
+// Kind identifies what a GC-relevant execution-trace event represents.
+type Kind string
+
+const (
+	GCStart           Kind = "GCStart"
+	GCDone            Kind = "GCDone"
+	GCSTWStart        Kind = "GCSTWStart"
+	GCSTWDone         Kind = "GCSTWDone"
+	GCMarkAssistStart Kind = "GCMarkAssistStart"
+	GCMarkAssistDone  Kind = "GCMarkAssistDone"
+	GCSweepStart      Kind = "GCSweepStart"
+	GCSweepDone       Kind = "GCSweepDone"
+)
+
+// Event is one GC-relevant event reduced from the execution trace.
+type Event struct {
+	Kind Kind
+
+	// Timestamp is seconds since the first event in the trace.
+	Timestamp float64
+
+	// Goroutine is the goroutine the event is scoped to, or -1 if the
+	// event (e.g. GCStart/GCDone) isn't goroutine-scoped.
+	Goroutine int64
+
+	// STWReason is set on GCSTWStart/GCSTWDone, e.g. "GC mark termination".
+	STWReason string
+
+	// DurationMs is set on *Done events: the time elapsed since the
+	// matching Start event for the same kind and goroutine, in ms.
+	DurationMs float64
+}
+
+// ToCSVHeader returns the CSV header row. Event implements the same
+// csvRecord interface as GCTrace so both event kinds can share one CSV
+// writer path, but the two have different columns, not a common shape.
+func (e *Event) ToCSVHeader() []string {
+	return []string{"Kind", "Timestamp", "Goroutine", "STWReason", "DurationMs"}
+}
+
+// ToCSVRow returns the CSV data row for e.
+func (e *Event) ToCSVRow() []string {
+	return []string{
+		string(e.Kind),
+		strconv.FormatFloat(e.Timestamp, 'f', -1, 64),
+		strconv.FormatInt(e.Goroutine, 10),
+		e.STWReason,
+		strconv.FormatFloat(e.DurationMs, 'f', -1, 64),
+	}
+}
@@ -0,0 +1,190 @@
+package exectrace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	xtrace "golang.org/x/exp/trace"
+)
+
+// TraceEventSource produces a stream of GC-relevant Events, decoupled
+// from how the underlying runtime/trace file is decoded. This lets the
+// CSV writer path stay agnostic to the trace format version.
+type TraceEventSource interface {
+	// Next returns the next Event, or an io.EOF error once the source is
+	// exhausted.
+	Next() (Event, error)
+}
+
+// pendingKey identifies one open (unmatched) range: a kind of event
+// together with the goroutine it's scoped to, or -1 for global ranges.
+type pendingKey struct {
+	kind      rangeKind
+	goroutine int64
+}
+
+// FileSource reads GC-relevant Events out of a binary runtime/trace
+// file, via golang.org/x/exp/trace's generation-based reader.
+type FileSource struct {
+	r       *xtrace.Reader
+	start   xtrace.Time
+	started bool
+	pending map[pendingKey]float64 // start timestamp (seconds), keyed by kind+goroutine
+}
+
+// NewFileSource wraps r, an open binary runtime/trace file, as a
+// TraceEventSource.
+func NewFileSource(r io.Reader) (*FileSource, error) {
+	tr, err := xtrace.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("exectrace: %w", err)
+	}
+	return &FileSource{r: tr, pending: make(map[pendingKey]float64)}, nil
+}
+
+// Next implements TraceEventSource.
+func (s *FileSource) Next() (Event, error) {
+	for {
+		raw, err := s.r.ReadEvent()
+		if err != nil {
+			return Event{}, err
+		}
+
+		if !s.started {
+			s.start = raw.Time()
+			s.started = true
+		}
+
+		ev, ok := s.translate(raw)
+		if ok {
+			return ev, nil
+		}
+	}
+}
+
+func (s *FileSource) elapsed(t xtrace.Time) float64 {
+	return t.Sub(s.start).Seconds()
+}
+
+// translate reduces one raw execution-trace event down to a GC-relevant
+// Event, if it's one we care about. GC, STW, mark-assist and sweep are
+// all represented in the trace as ranges: a Begin/Active event opens
+// one, an End event closes it; we pair them up by kind and goroutine to
+// compute DurationMs on the Done event.
+func (s *FileSource) translate(raw xtrace.Event) (Event, bool) {
+	switch raw.Kind() {
+	case xtrace.EventRangeBegin, xtrace.EventRangeActive:
+		kind, goroutine, reason, ok := classify(raw.Range())
+		if !ok {
+			return Event{}, false
+		}
+		ts := s.elapsed(raw.Time())
+		s.pending[pendingKey{kind, goroutine}] = ts
+		return Event{
+			Kind:      startKind(kind),
+			Timestamp: ts,
+			Goroutine: goroutine,
+			STWReason: reason,
+		}, true
+
+	case xtrace.EventRangeEnd:
+		kind, goroutine, reason, ok := classify(raw.Range())
+		if !ok {
+			return Event{}, false
+		}
+		ts := s.elapsed(raw.Time())
+
+		var durationMs float64
+		key := pendingKey{kind, goroutine}
+		if startTs, had := s.pending[key]; had {
+			durationMs = (ts - startTs) * 1000
+			delete(s.pending, key)
+		}
+
+		return Event{
+			Kind:       doneKind(kind),
+			Timestamp:  ts,
+			Goroutine:  goroutine,
+			STWReason:  reason,
+			DurationMs: durationMs,
+		}, true
+
+	default:
+		return Event{}, false
+	}
+}
+
+// rangeKind is the logical GC activity a trace Range represents,
+// independent of whether we're looking at its Begin, Active, or End event.
+type rangeKind int
+
+const (
+	rangeGC rangeKind = iota
+	rangeSTW
+	rangeMarkAssist
+	rangeSweep
+)
+
+func startKind(k rangeKind) Kind {
+	switch k {
+	case rangeSTW:
+		return GCSTWStart
+	case rangeMarkAssist:
+		return GCMarkAssistStart
+	case rangeSweep:
+		return GCSweepStart
+	default:
+		return GCStart
+	}
+}
+
+func doneKind(k rangeKind) Kind {
+	switch k {
+	case rangeSTW:
+		return GCSTWDone
+	case rangeMarkAssist:
+		return GCMarkAssistDone
+	case rangeSweep:
+		return GCSweepDone
+	default:
+		return GCDone
+	}
+}
+
+// classify maps a trace.Range to the GC activity it represents, the
+// goroutine it's scoped to (-1 if none), and its STW reason (only set
+// for rangeSTW). ok is false for ranges unrelated to GC (e.g. user
+// regions), which the caller should skip.
+func classify(r xtrace.Range) (kind rangeKind, goroutine int64, reason string, ok bool) {
+	switch {
+	case strings.HasPrefix(r.Name, "stop-the-world"):
+		return rangeSTW, scopeGoroutine(r), stwReason(r.Name), true
+	case r.Name == "GC concurrent mark phase":
+		return rangeGC, -1, "", true
+	case r.Name == "GC mark assist":
+		return rangeMarkAssist, scopeGoroutine(r), "", true
+	case r.Name == "GC incremental sweep":
+		return rangeSweep, -1, "", true
+	default:
+		return 0, -1, "", false
+	}
+}
+
+func scopeGoroutine(r xtrace.Range) int64 {
+	if r.Scope.Kind != xtrace.ResourceGoroutine {
+		return -1
+	}
+	return int64(r.Scope.Goroutine())
+}
+
+// stwReason extracts the reason from a range name of the form
+// "stop-the-world (reason)".
+func stwReason(name string) string {
+	open := strings.Index(name, "(")
+	close := strings.LastIndex(name, ")")
+	if open < 0 || close <= open {
+		return ""
+	}
+	return name[open+1 : close]
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KunalSin9h/memgc/exectrace"
+)
+
+// runExecTrace implements the --exec-trace mode: it parses a binary
+// runtime/trace file and writes a CSV of GC-relevant events, going
+// through the same writeCSVRecord path as the gctrace CSV output.
+func runExecTrace(inputPath, outputPath string) {
+	in, err := os.Open(inputPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening exec trace file: %v\n", err)
+		os.Exit(1)
+	}
+	defer in.Close()
+
+	source, err := exectrace.NewFileSource(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading exec trace file: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating exec trace CSV file: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := out.Close(); err != nil {
+			fmt.Printf("Erorr: %s\n", err.Error())
+		}
+	}()
+
+	csvWriter := csv.NewWriter(out)
+	defer csvWriter.Flush()
+	headerWritten := false
+
+	for {
+		ev, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading exec trace event: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeCSVRecord(csvWriter, out, &ev, &headerWritten, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing exec trace row: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -2,12 +2,19 @@ package main
 
 import (
 	"bufio"
-	"encoding/csv"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"regexp"
 	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/KunalSin9h/memgc/dashboard"
+	"github.com/KunalSin9h/memgc/summary"
+	"github.com/KunalSin9h/memgc/sysmetrics"
 )
 
 /*
@@ -64,11 +71,18 @@ type GCTrace struct {
 	HeapInUseAfter  int
 	HeapMarkedLive  int
 	HeapGoal        int
-	StacksMB        int
-	GlobalsMB       int
+
+	// StacksMB and GlobalsMB are -1 when the trace format the line matched
+	// against doesn't carry these fields (pre-1.16 gctrace output).
+	StacksMB  int
+	GlobalsMB int
 
 	// Processors
 	NumProcs int
+
+	// Forced is true when the line ends in "(forced)", meaning the GC was
+	// triggered by an explicit runtime.GC() call rather than the pacer.
+	Forced bool
 }
 
 type ParserError int
@@ -79,54 +93,119 @@ const (
 	NoError
 )
 
-func parseGCTrace(line string) (*GCTrace, ParserError) {
-	// Pattern for: gc 2553 @8.452s 14%: 0.004+0.33+0.051 ms clock, 0.056+0.12/0.56/0.94+0.61 ms cpu, 4->4->2 MB, 5 MB goal, 12 P
-	pattern := `gc\s+(\d+)\s+@([\d.]+)s\s+([\d.]+)%:\s+([\d.]+)\+([\d.]+)\+([\d.]+)\s+ms\s+clock,\s+([\d.]+)\+([\d.]+)/([\d.]+)/([\d.]+)\+([\d.]+)\s+ms\s+cpu,\s+(\d+)->(\d+)->(\d+)\s+MB,\s+(\d+)\s+MB\s+goal,\s+(\d+)\s+MB\s+stacks,\s+(\d+)\s+MB\s+globals,\s+(\d+)\s+P`
+// gcTraceShape describes one gctrace line layout and how to turn its
+// regex submatches into a GCTrace. Different Go toolchains have emitted
+// different shapes over time (e.g. the "N MB stacks, N MB globals"
+// fields were added in Go 1.16), so we try each shape in turn rather
+// than relying on a single pattern.
+type gcTraceShape struct {
+	name string
+	re   *regexp.Regexp
+	// build constructs a GCTrace from the shape's named submatches.
+	build func(m map[string]string) *GCTrace
+}
 
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return nil, RegexCompileFail
+// commonFields is the portion of the gctrace line that every shape below
+// shares: the run number, elapsed time, CPU%, wall/CPU phase breakdown,
+// and heap before/after/live/goal. Shapes differ only in what comes
+// after "MB goal," and whether the line ends in "(forced)".
+const commonFields = `gc\s+(?P<num>\d+)\s+@(?P<ts>[\d.]+)s\s+(?P<cpupct>[\d.]+)%:\s+` +
+	`(?P<wallwb>[\d.]+)\+(?P<wallconc>[\d.]+)\+(?P<wallmt>[\d.]+)\s+ms\s+clock,\s+` +
+	`(?P<cpuwb>[\d.]+)\+(?P<cpuassist>[\d.]+)/(?P<cpubg>[\d.]+)/(?P<cpuidle>[\d.]+)\+(?P<cpumt>[\d.]+)\s+ms\s+cpu,\s+` +
+	`(?P<before>\d+)->(?P<after>\d+)->(?P<live>\d+)\s+MB,\s+(?P<goal>\d+)\s+MB\s+goal,\s+`
+
+// gcTraceShapes is tried in order from most specific to least specific;
+// the first shape whose regex matches the line wins. Compiled once at
+// init time rather than per line.
+var gcTraceShapes = []gcTraceShape{
+	{
+		// Go 1.16+: adds "N MB stacks, N MB globals" before the P count,
+		// and an optional "(forced)" suffix for explicit runtime.GC() runs.
+		name: "stacks-globals",
+		re: regexp.MustCompile(`^` + commonFields +
+			`(?P<stacks>\d+)\s+MB\s+stacks,\s+(?P<globals>\d+)\s+MB\s+globals,\s+(?P<procs>\d+)\s+P(?:\s+\((?P<forced>forced)\))?\s*$`),
+		build: func(m map[string]string) *GCTrace {
+			t := newGCTraceFromCommon(m)
+			t.StacksMB = atoi(m["stacks"])
+			t.GlobalsMB = atoi(m["globals"])
+			return t
+		},
+	},
+	{
+		// Pre-1.16: no stacks/globals fields, just the P count and an
+		// optional "(forced)" suffix.
+		name: "legacy",
+		re: regexp.MustCompile(`^` + commonFields +
+			`(?P<procs>\d+)\s+P(?:\s+\((?P<forced>forced)\))?\s*$`),
+		build: func(m map[string]string) *GCTrace {
+			t := newGCTraceFromCommon(m)
+			t.StacksMB = -1
+			t.GlobalsMB = -1
+			return t
+		},
+	},
+}
+
+// newGCTraceFromCommon fills in the fields shared by every gctrace shape
+// from a regex submatch map keyed by group name.
+func newGCTraceFromCommon(m map[string]string) *GCTrace {
+	return &GCTrace{
+		GCNum:               atoi(m["num"]),
+		Timestamp:           atof(m["ts"]),
+		CPUPercent:          atof(m["cpupct"]),
+		WallSTWWriteBarrier: atof(m["wallwb"]),
+		WallConcurrent:      atof(m["wallconc"]),
+		WallSTWMarkTerm:     atof(m["wallmt"]),
+		CPUSTWWriteBarrier:  atof(m["cpuwb"]),
+		CPUMarkAssist:       atof(m["cpuassist"]),
+		CPUMarkBackground:   atof(m["cpubg"]),
+		CPUMarkIdle:         atof(m["cpuidle"]),
+		CPUSTWMarkTerm:      atof(m["cpumt"]),
+		HeapInUseBefore:     atoi(m["before"]),
+		HeapInUseAfter:      atoi(m["after"]),
+		HeapMarkedLive:      atoi(m["live"]),
+		HeapGoal:            atoi(m["goal"]),
+		NumProcs:            atoi(m["procs"]),
+		Forced:              m["forced"] == "forced",
 	}
-	matches := re.FindStringSubmatch(line)
+}
+
+func atof(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
 
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
+
+// namedSubmatches runs re against line and returns its named capture
+// groups as a map, or nil if the line doesn't match.
+func namedSubmatches(re *regexp.Regexp, line string) map[string]string {
+	matches := re.FindStringSubmatch(line)
 	if matches == nil {
-		return nil, LineNotMatch
-	}
-
-	// Helper to parse float
-	parseFloat := func(s string) float64 {
-		v, _ := strconv.ParseFloat(s, 64)
-		return v
-	}
-
-	// Helper to parse int
-	parseInt := func(s string) int {
-		v, _ := strconv.Atoi(s)
-		return v
-	}
-
-	trace := &GCTrace{
-		GCNum:               parseInt(matches[1]),
-		Timestamp:           parseFloat(matches[2]),
-		CPUPercent:          parseFloat(matches[3]),
-		WallSTWWriteBarrier: parseFloat(matches[4]),
-		WallConcurrent:      parseFloat(matches[5]),
-		WallSTWMarkTerm:     parseFloat(matches[6]),
-		CPUSTWWriteBarrier:  parseFloat(matches[7]),
-		CPUMarkAssist:       parseFloat(matches[8]),
-		CPUMarkBackground:   parseFloat(matches[9]),
-		CPUMarkIdle:         parseFloat(matches[10]),
-		CPUSTWMarkTerm:      parseFloat(matches[11]),
-		HeapInUseBefore:     parseInt(matches[12]),
-		HeapInUseAfter:      parseInt(matches[13]),
-		HeapMarkedLive:      parseInt(matches[14]),
-		HeapGoal:            parseInt(matches[15]),
-		StacksMB:            parseInt(matches[16]),
-		GlobalsMB:           parseInt(matches[17]),
-		NumProcs:            parseInt(matches[18]),
-	}
-
-	return trace, NoError
+		return nil
+	}
+	result := make(map[string]string, len(matches))
+	for i, name := range re.SubexpNames() {
+		if i == 0 || name == "" {
+			continue
+		}
+		result[name] = matches[i]
+	}
+	return result
+}
+
+func parseGCTrace(line string) (*GCTrace, ParserError) {
+	for _, shape := range gcTraceShapes {
+		m := namedSubmatches(shape.re, line)
+		if m == nil {
+			continue
+		}
+		return shape.build(m), NoError
+	}
+	return nil, LineNotMatch
 }
 
 // ToCSVHeader returns CSV header row
@@ -150,6 +229,7 @@ func (t *GCTrace) ToCSVHeader() []string {
 		"StacksMB",
 		"GlobalsMB",
 		"NumProcs",
+		"Forced",
 	}
 }
 
@@ -174,29 +254,100 @@ func (t *GCTrace) ToCSVRow() []string {
 		strconv.Itoa(t.StacksMB),
 		strconv.Itoa(t.GlobalsMB),
 		strconv.Itoa(t.NumProcs),
+		strconv.FormatBool(t.Forced),
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "convert" {
+		runConvert(os.Args[2:])
+		return
+	}
+
 	csvOutput := flag.String("csv", "data.csv", "GC Trace output in CSV format (e.g., data.csv). If empty, prints to stdout.")
+	summaryEnabled := flag.Bool("summary", false, "Also emit an aggregate summary (count/mean/percentiles) alongside the per-event CSV.")
+	summaryOutput := flag.String("summary-out", "summary.csv", "Path for the aggregate summary produced by --summary.")
+	window := flag.Duration("window", 0, "Emit rollup summaries every N seconds of trace time (e.g. --window=30s). Implies --summary.")
+	rollupOutput := flag.String("rollup-out", "rollup.csv", "Path for the windowed rollups produced by --window.")
+	serveAddr := flag.String("serve", "", "Serve a live dashboard at this address (e.g. --serve=:8080), alongside the CSV output.")
+	withSystemMetrics := flag.Bool("with-system-metrics", false, "Join each row with host resource metrics (process RSS, system CPU%, load average, available memory).")
+	systemMetricsInterval := flag.Duration("system-metrics-interval", time.Second, "How often to sample host metrics when --with-system-metrics is set.")
+	execTraceInput := flag.String("exec-trace", "", "Parse a binary runtime/trace file (e.g. --exec-trace=trace.out) instead of reading gctrace lines from stdin. Requires a trace produced by Go 1.22+.")
+	execTraceOutput := flag.String("exec-trace-out", "exec-trace.csv", "CSV output path for --exec-trace.")
+	format := flag.String("format", "csv", "Per-event output format: csv, or bin for a zstd-compressed, gob-encoded capture.")
+	durable := flag.Bool("durable", false, "Fsync the output file after every row. Off by default since it dominates runtime on long captures.")
 	flag.Parse()
 
-	csvFile, err := os.Create(*csvOutput)
+	if *execTraceInput != "" {
+		runExecTrace(*execTraceInput, *execTraceOutput)
+		return
+	}
+
+	var sysCollector *sysmetrics.Collector
+	if *withSystemMetrics {
+		src, err := sysmetrics.NewGopsutilSource()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting system metrics source: %v\n", err)
+			os.Exit(1)
+		}
+		sysCollector = sysmetrics.NewCollector(src, *systemMetricsInterval)
+		defer sysCollector.Stop()
+	}
+
+	var dash *dashboard.Server
+	if *serveAddr != "" {
+		dash = dashboard.NewServer()
+		go func() {
+			if err := http.ListenAndServe(*serveAddr, dash.Handler()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error serving dashboard: %v\n", err)
+				os.Exit(1)
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "Dashboard listening on %s\n", *serveAddr)
+	}
+
+	if *window > 0 {
+		*summaryEnabled = true
+	}
+
+	var overall *summary.Summarizer
+	if *summaryEnabled {
+		overall = summary.NewSummarizer()
+	}
+
+	var (
+		windowSummarizer *summary.Summarizer
+		windowStart      float64
+		windowOpen       bool
+		rollups          *rollupWriter
+	)
+	if *window > 0 {
+		var err error
+		rollups, err = newRollupWriter(*rollupOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating rollup file: %v\n", err)
+			os.Exit(1)
+		}
+		defer rollups.Close()
+		windowSummarizer = summary.NewSummarizer()
+	}
+	windowSeconds := window.Seconds()
+
+	sink, err := newRecordSink(*csvOutput, *format, *durable)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating CSV file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
 		os.Exit(1)
 	}
 	defer func() {
-		if err := csvFile.Close(); err != nil {
+		if err := sink.Close(); err != nil {
 			fmt.Printf("Erorr: %s\n", err.Error())
 		}
 	}()
 
-	csvWriter := csv.NewWriter(csvFile)
-	defer csvWriter.Flush()
-
 	scanner := bufio.NewScanner(os.Stdin)
-	headerWritten := false
+
+	var lastEventTimestamp float64
+	haveLastEvent := false
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -212,32 +363,55 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Write CSV header on first successful parse
-		if !headerWritten {
-			if err := csvWriter.Write(gcTrace.ToCSVHeader()); err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing CSV header: %v\n", err)
-				os.Exit(1)
-			}
-			headerWritten = true
+		var rec csvRecord = gcTrace
+		if sysCollector != nil {
+			sysSample, _ := sysCollector.Nearest(gcTrace.Timestamp)
+			rec = gcTraceWithSystemMetrics{trace: gcTrace, sys: sysSample}
 		}
-
-		// Write CSV row
-		if err := csvWriter.Write(gcTrace.ToCSVRow()); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing CSV row: %v\n", err)
+		if err := sink.Write(rec); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output row: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Flush immediately after each row
-		csvWriter.Flush()
-		if err := csvWriter.Error(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error flushing CSV row: %v\n", err)
-			os.Exit(1)
+		if dash != nil {
+			gcHz := 0.0
+			if haveLastEvent && gcTrace.Timestamp > lastEventTimestamp {
+				gcHz = 1 / (gcTrace.Timestamp - lastEventTimestamp)
+			}
+			lastEventTimestamp = gcTrace.Timestamp
+			haveLastEvent = true
+
+			dash.Broadcast(dashboard.Event{
+				GCNum:      gcTrace.GCNum,
+				Timestamp:  gcTrace.Timestamp,
+				PauseMs:    gcTrace.WallSTWWriteBarrier + gcTrace.WallSTWMarkTerm,
+				HeapInUse:  gcTrace.HeapInUseAfter,
+				HeapGoal:   gcTrace.HeapGoal,
+				CPUPercent: gcTrace.CPUPercent,
+				GCHz:       gcHz,
+			})
 		}
 
-		// Sync to disk for maximum durability
-		if err := csvFile.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error syncing CSV file: %v\n", err)
-			os.Exit(1)
+		if overall != nil {
+			sample := sampleFromTrace(gcTrace)
+			overall.Add(sample)
+
+			if windowSummarizer != nil {
+				if !windowOpen {
+					windowStart = gcTrace.Timestamp
+					windowOpen = true
+				}
+				windowSummarizer.Add(sample)
+
+				if gcTrace.Timestamp-windowStart >= windowSeconds {
+					if err := rollups.writeWindow(windowStart, gcTrace.Timestamp, windowSummarizer.Snapshot()); err != nil {
+						fmt.Fprintf(os.Stderr, "Error writing rollup window: %v\n", err)
+						os.Exit(1)
+					}
+					windowSummarizer = summary.NewSummarizer()
+					windowOpen = false
+				}
+			}
 		}
 	}
 
@@ -245,4 +419,27 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
 		os.Exit(1)
 	}
+
+	if windowOpen {
+		if err := rollups.writeWindow(windowStart, windowStart+windowSeconds, windowSummarizer.Snapshot()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing final rollup window: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if overall != nil {
+		if err := writeSummaryFile(*summaryOutput, overall.Snapshot()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing summary file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if dash != nil {
+		// The trace source has closed stdin, but keep the dashboard up so
+		// the final chart stays visible until the user shuts it down.
+		fmt.Fprintln(os.Stderr, "Trace ended; dashboard still serving. Press Ctrl+C to exit.")
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+	}
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+
+	"github.com/KunalSin9h/memgc/binfmt"
+)
+
+// recordSink is the output abstraction for the main per-event capture:
+// csvSink writes the original plain-CSV format, binSink writes the
+// compressed --format=bin capture. Both buffer writes and only fsync
+// when durable is set, since unconditional fsync-per-row dominates
+// runtime on long captures.
+type recordSink interface {
+	Write(rec csvRecord) error
+	Close() error
+}
+
+// newRecordSink opens path for the requested format ("csv" or "bin").
+func newRecordSink(path, format string, durable bool) (recordSink, error) {
+	switch format {
+	case "csv":
+		return newCSVRecordSink(path, durable)
+	case "bin":
+		return newBinRecordSink(path, durable)
+	default:
+		return nil, fmt.Errorf("unknown --format %q (want csv or bin)", format)
+	}
+}
+
+type csvRecordSink struct {
+	f             *os.File
+	w             *csv.Writer
+	durable       bool
+	headerWritten bool
+}
+
+func newCSVRecordSink(path string, durable bool) (*csvRecordSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &csvRecordSink{f: f, w: csv.NewWriter(f), durable: durable}, nil
+}
+
+func (s *csvRecordSink) Write(rec csvRecord) error {
+	return writeCSVRecord(s.w, s.f, rec, &s.headerWritten, s.durable)
+}
+
+func (s *csvRecordSink) Close() error {
+	s.w.Flush()
+	if err := s.w.Error(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+// binRecordSink writes the zstd-compressed, gob-encoded --format=bin
+// capture via the binfmt package.
+type binRecordSink struct {
+	f             *os.File
+	w             *binfmt.Writer
+	durable       bool
+	headerWritten bool
+}
+
+func newBinRecordSink(path string, durable bool) (*binRecordSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &binRecordSink{f: f, durable: durable}, nil
+}
+
+func (s *binRecordSink) Write(rec csvRecord) error {
+	if !s.headerWritten {
+		w, err := binfmt.NewWriter(s.f, rec.ToCSVHeader())
+		if err != nil {
+			return err
+		}
+		s.w = w
+		s.headerWritten = true
+	}
+
+	if err := s.w.WriteRow(rec.ToCSVRow()); err != nil {
+		return err
+	}
+
+	if s.durable {
+		return s.w.Sync()
+	}
+	return s.w.Flush()
+}
+
+func (s *binRecordSink) Close() error {
+	if s.w == nil {
+		return s.f.Close()
+	}
+	return s.w.Close()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+
+	"github.com/KunalSin9h/memgc/summary"
+)
+
+// sampleFromTrace translates a parsed GCTrace into the reduced Sample
+// shape the summary package aggregates over.
+func sampleFromTrace(t *GCTrace) summary.Sample {
+	return summary.Sample{
+		Timestamp:           t.Timestamp,
+		CPUPercent:          t.CPUPercent,
+		WallSTWWriteBarrier: t.WallSTWWriteBarrier,
+		WallConcurrent:      t.WallConcurrent,
+		WallSTWMarkTerm:     t.WallSTWMarkTerm,
+		HeapInUseAfter:      t.HeapInUseAfter,
+	}
+}
+
+// summaryHeader is the column layout shared by the overall summary file
+// and the windowed rollup file, aside from the leading window-bounds
+// columns the rollup file adds.
+var summaryHeader = []string{"Metric", "Count", "Mean", "P50", "P90", "P95", "P99", "Max"}
+
+// summaryRows renders a Summary as one row per measured phase, plus
+// trailing rows for the scalar, whole-window metrics (heap growth rate,
+// GC frequency, total STW time), which only populate the Mean column.
+func summaryRows(s summary.Summary) [][]string {
+	phaseRow := func(name string, p summary.PhaseStats) []string {
+		return []string{
+			name,
+			strconv.Itoa(p.Count),
+			strconv.FormatFloat(p.Mean, 'f', -1, 64),
+			strconv.FormatFloat(p.P50, 'f', -1, 64),
+			strconv.FormatFloat(p.P90, 'f', -1, 64),
+			strconv.FormatFloat(p.P95, 'f', -1, 64),
+			strconv.FormatFloat(p.P99, 'f', -1, 64),
+			strconv.FormatFloat(p.Max, 'f', -1, 64),
+		}
+	}
+	scalarRow := func(name string, value float64) []string {
+		return []string{name, strconv.Itoa(s.Count), strconv.FormatFloat(value, 'f', -1, 64), "", "", "", "", ""}
+	}
+
+	return [][]string{
+		phaseRow("WallSTWWriteBarrier", s.WallSTWWriteBarrier),
+		phaseRow("WallConcurrent", s.WallConcurrent),
+		phaseRow("WallSTWMarkTerm", s.WallSTWMarkTerm),
+		phaseRow("CPUPercent", s.CPUPercent),
+		scalarRow("HeapGrowthMBPerSec", s.HeapGrowthMBPerSec),
+		scalarRow("GCFrequencyHz", s.GCFrequencyHz),
+		scalarRow("TotalSTWMs", s.TotalSTWMs),
+	}
+}
+
+// writeSummaryFile writes a single Summary snapshot to path as CSV.
+func writeSummaryFile(path string, s summary.Summary) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(summaryHeader); err != nil {
+		return err
+	}
+	for _, row := range summaryRows(s) {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// rollupWriter appends one windowed Summary at a time to a CSV file,
+// prefixing each metric row with the window's start/end trace time.
+type rollupWriter struct {
+	file *os.File
+	w    *csv.Writer
+}
+
+func newRollupWriter(path string) (*rollupWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := csv.NewWriter(f)
+	header := append([]string{"WindowStart", "WindowEnd"}, summaryHeader...)
+	if err := w.Write(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &rollupWriter{file: f, w: w}, nil
+}
+
+func (r *rollupWriter) writeWindow(start, end float64, s summary.Summary) error {
+	bounds := []string{
+		strconv.FormatFloat(start, 'f', -1, 64),
+		strconv.FormatFloat(end, 'f', -1, 64),
+	}
+	for _, row := range summaryRows(s) {
+		if err := r.w.Write(append(append([]string{}, bounds...), row...)); err != nil {
+			return err
+		}
+	}
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func (r *rollupWriter) Close() error {
+	r.w.Flush()
+	if err := r.w.Error(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,109 @@
+package summary
+
+import "sort"
+
+// compressionFactor is the t-digest-style compression parameter (often
+// called delta or k). Higher values keep more centroids and give more
+// accurate quantiles at the cost of memory.
+const compressionFactor = 100
+
+// centroid is a weighted mean: the mean of `count` samples that have
+// been merged together because they were close enough not to affect
+// quantile accuracy at their position in the distribution.
+type centroid struct {
+	mean  float64
+	count int
+}
+
+// digest is a streaming quantile estimator in the spirit of t-digest: it
+// keeps a small, bounded set of centroids instead of every sample, so
+// memory stays flat no matter how long the trace runs. New samples are
+// appended as singleton centroids and merged into their neighbors once
+// the centroid count grows past a threshold.
+type digest struct {
+	centroids []centroid
+	sorted    bool
+	total     int
+}
+
+func newDigest() *digest {
+	return &digest{}
+}
+
+func (d *digest) add(v float64) {
+	d.centroids = append(d.centroids, centroid{mean: v, count: 1})
+	d.total++
+	d.sorted = false
+	if len(d.centroids) > compressionFactor*4 {
+		d.compress()
+	}
+}
+
+// sizeBound is the maximum weight a centroid may carry at quantile
+// position q: k/(4*q*(1-q)). It shrinks near the tails, where a wrong
+// merge would distort p99-style quantiles the most, and grows near the
+// median, where merging many samples barely moves the estimate.
+func sizeBound(q float64) float64 {
+	if q < 1e-6 {
+		q = 1e-6
+	}
+	if q > 1-1e-6 {
+		q = 1 - 1e-6
+	}
+	return compressionFactor / (4 * q * (1 - q))
+}
+
+// compress sorts the centroids by mean and greedily merges adjacent
+// ones that fit within their position's size bound, shrinking the
+// centroid count back down.
+func (d *digest) compress() {
+	if len(d.centroids) == 0 {
+		return
+	}
+	d.sortCentroids()
+
+	merged := make([]centroid, 0, compressionFactor)
+	cur := d.centroids[0]
+	seen := 0
+
+	for _, c := range d.centroids[1:] {
+		q := (float64(seen) + float64(cur.count)/2) / float64(d.total)
+		if float64(cur.count+c.count) <= sizeBound(q) {
+			cur.mean = (cur.mean*float64(cur.count) + c.mean*float64(c.count)) / float64(cur.count+c.count)
+			cur.count += c.count
+			continue
+		}
+		seen += cur.count
+		merged = append(merged, cur)
+		cur = c
+	}
+	merged = append(merged, cur)
+	d.centroids = merged
+}
+
+func (d *digest) sortCentroids() {
+	if d.sorted {
+		return
+	}
+	sort.Slice(d.centroids, func(i, j int) bool { return d.centroids[i].mean < d.centroids[j].mean })
+	d.sorted = true
+}
+
+// quantile returns an estimate of the q-th quantile (0 <= q <= 1) of the
+// samples seen so far.
+func (d *digest) quantile(q float64) float64 {
+	if len(d.centroids) == 0 {
+		return 0
+	}
+	d.sortCentroids()
+
+	target := q * float64(d.total)
+	cumulative := 0.0
+	for _, c := range d.centroids {
+		cumulative += float64(c.count)
+		if cumulative >= target {
+			return c.mean
+		}
+	}
+	return d.centroids[len(d.centroids)-1].mean
+}
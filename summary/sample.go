@@ -0,0 +1,25 @@
+// Package summary implements streaming aggregation over a sequence of
+// GC events: running counts/means, bounded-memory percentile estimates,
+// and derived rates such as heap growth and GC frequency.
+//
+// It's deliberately decoupled from any one trace format (gctrace,
+// runtime/trace, ...) so a Summarizer can be fed by whichever parser
+// produced the events; callers translate their own event type into a
+// Sample before calling Summarizer.Add.
+package summary
+
+// Sample is one GC event's measurements, as needed for aggregation.
+type Sample struct {
+	// Timestamp is seconds since the traced program started.
+	Timestamp float64
+
+	CPUPercent float64
+
+	// Wall-clock phase durations, in ms.
+	WallSTWWriteBarrier float64
+	WallConcurrent      float64
+	WallSTWMarkTerm     float64
+
+	// HeapInUseAfter is the heap in-use after marking finished, in MB.
+	HeapInUseAfter int
+}
@@ -0,0 +1,143 @@
+package summary
+
+// PhaseStats holds the aggregate view of one measured quantity (a wall
+// clock phase, CPU%, etc.) across every sample seen.
+type PhaseStats struct {
+	Count int
+	Mean  float64
+	P50   float64
+	P90   float64
+	P95   float64
+	P99   float64
+	Max   float64
+}
+
+// tracker pairs a streaming digest with the running sum/max needed to
+// report Mean and Max without re-scanning samples.
+type tracker struct {
+	d     *digest
+	sum   float64
+	max   float64
+	count int
+}
+
+func newTracker() *tracker {
+	return &tracker{d: newDigest()}
+}
+
+func (t *tracker) add(v float64) {
+	t.d.add(v)
+	t.sum += v
+	t.count++
+	if v > t.max {
+		t.max = v
+	}
+}
+
+func (t *tracker) stats() PhaseStats {
+	mean := 0.0
+	if t.count > 0 {
+		mean = t.sum / float64(t.count)
+	}
+	return PhaseStats{
+		Count: t.count,
+		Mean:  mean,
+		P50:   t.d.quantile(0.50),
+		P90:   t.d.quantile(0.90),
+		P95:   t.d.quantile(0.95),
+		P99:   t.d.quantile(0.99),
+		Max:   t.max,
+	}
+}
+
+// Summary is a point-in-time snapshot of everything a Summarizer has
+// accumulated.
+type Summary struct {
+	Count int
+
+	WallSTWWriteBarrier PhaseStats
+	WallConcurrent      PhaseStats
+	WallSTWMarkTerm     PhaseStats
+	CPUPercent          PhaseStats
+
+	// HeapGrowthMBPerSec is the average rate of change of heap in-use
+	// across the window, in MB/s. Negative when the heap shrank overall.
+	HeapGrowthMBPerSec float64
+
+	// GCFrequencyHz is the average number of GC cycles per second.
+	GCFrequencyHz float64
+
+	// TotalSTWMs is the sum of every stop-the-world phase (write
+	// barrier + mark termination) across the window, in ms.
+	TotalSTWMs float64
+}
+
+// Summarizer accumulates streaming statistics over a sequence of GC
+// Samples. It keeps bounded memory regardless of stream length: each
+// tracked quantity is backed by a digest with a fixed centroid budget
+// rather than a slice of every sample seen.
+//
+// Summarizer is not safe for concurrent use.
+type Summarizer struct {
+	count int
+
+	firstTimestamp, lastTimestamp float64
+	firstHeap, lastHeap           int
+
+	totalSTWMs float64
+
+	cpuPercent          *tracker
+	wallSTWWriteBarrier *tracker
+	wallConcurrent      *tracker
+	wallSTWMarkTerm     *tracker
+}
+
+// NewSummarizer returns an empty Summarizer ready to accept Samples.
+func NewSummarizer() *Summarizer {
+	return &Summarizer{
+		cpuPercent:          newTracker(),
+		wallSTWWriteBarrier: newTracker(),
+		wallConcurrent:      newTracker(),
+		wallSTWMarkTerm:     newTracker(),
+	}
+}
+
+// Add folds one Sample into the running aggregates.
+func (s *Summarizer) Add(sample Sample) {
+	if s.count == 0 {
+		s.firstTimestamp = sample.Timestamp
+		s.firstHeap = sample.HeapInUseAfter
+	}
+	s.count++
+	s.lastTimestamp = sample.Timestamp
+	s.lastHeap = sample.HeapInUseAfter
+	s.totalSTWMs += sample.WallSTWWriteBarrier + sample.WallSTWMarkTerm
+
+	s.cpuPercent.add(sample.CPUPercent)
+	s.wallSTWWriteBarrier.add(sample.WallSTWWriteBarrier)
+	s.wallConcurrent.add(sample.WallConcurrent)
+	s.wallSTWMarkTerm.add(sample.WallSTWMarkTerm)
+}
+
+// Snapshot returns the aggregate metrics computed over every Sample
+// added so far. It's safe to keep calling Add after taking a snapshot,
+// e.g. to emit windowed rollups from one long-lived Summarizer.
+func (s *Summarizer) Snapshot() Summary {
+	elapsed := s.lastTimestamp - s.firstTimestamp
+
+	summary := Summary{
+		Count:               s.count,
+		WallSTWWriteBarrier: s.wallSTWWriteBarrier.stats(),
+		WallConcurrent:      s.wallConcurrent.stats(),
+		WallSTWMarkTerm:     s.wallSTWMarkTerm.stats(),
+		CPUPercent:          s.cpuPercent.stats(),
+		TotalSTWMs:          s.totalSTWMs,
+	}
+
+	if elapsed > 0 {
+		summary.HeapGrowthMBPerSec = float64(s.lastHeap-s.firstHeap) / elapsed
+		summary.GCFrequencyHz = float64(s.count) / elapsed
+	}
+
+	return summary
+}
@@ -0,0 +1,110 @@
+package sysmetrics
+
+import (
+	"sync"
+	"time"
+)
+
+// historyLimit bounds how many samples a Collector keeps in memory; old
+// samples are dropped once a gctrace line is unlikely to still need
+// them for a nearest-timestamp join.
+const historyLimit = 4096
+
+// Collector samples a MetricsSource on a fixed interval in the
+// background and keeps a bounded history so callers can join a sample
+// against the nearest one by elapsed time.
+//
+// Collector is safe for concurrent use.
+type Collector struct {
+	source MetricsSource
+	start  time.Time
+
+	mu         sync.Mutex
+	history    []Sample
+	stop       chan struct{}
+	offset     float64
+	haveOffset bool
+}
+
+// NewCollector starts sampling src every interval in a background
+// goroutine. Call Stop to end sampling.
+func NewCollector(src MetricsSource, interval time.Duration) *Collector {
+	c := &Collector{
+		source: src,
+		start:  time.Now(),
+		stop:   make(chan struct{}),
+	}
+	go c.run(interval)
+	return c
+}
+
+func (c *Collector) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			sample, err := c.source.Sample()
+			if err != nil {
+				// A transient read failure shouldn't kill the collector;
+				// just skip this tick and try again next time.
+				continue
+			}
+			sample.Elapsed = time.Since(c.start).Seconds()
+
+			c.mu.Lock()
+			c.history = append(c.history, sample)
+			if len(c.history) > historyLimit {
+				c.history = c.history[len(c.history)-historyLimit:]
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Stop ends background sampling.
+func (c *Collector) Stop() {
+	close(c.stop)
+}
+
+// Nearest returns the recorded sample whose Elapsed is closest to ts,
+// used to join a system-metrics sample onto a gctrace line by
+// timestamp. ts is on the traced program's clock (seconds since that
+// program started), which generally isn't the same instant memgc
+// itself started, so the first call calibrates a constant offset
+// between the two clocks from ts and assumes it holds for the rest of
+// the capture. The second return value is false if no sample has been
+// collected yet.
+func (c *Collector) Nearest(ts float64) (Sample, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.history) == 0 {
+		return Sample{}, false
+	}
+
+	if !c.haveOffset {
+		c.offset = time.Since(c.start).Seconds() - ts
+		c.haveOffset = true
+	}
+	ts += c.offset
+
+	best := c.history[0]
+	bestDiff := diff(best.Elapsed, ts)
+	for _, s := range c.history[1:] {
+		if d := diff(s.Elapsed, ts); d < bestDiff {
+			best, bestDiff = s, d
+		}
+	}
+	return best, true
+}
+
+func diff(a, b float64) float64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
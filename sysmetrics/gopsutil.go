@@ -0,0 +1,59 @@
+package sysmetrics
+
+import (
+	"os"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// gopsutilSource is the default MetricsSource, backed by gopsutil. It
+// reports RSS for the current process plus system-wide CPU, load, and
+// available memory.
+type gopsutilSource struct {
+	proc *process.Process
+}
+
+// NewGopsutilSource returns a MetricsSource that reads host metrics via
+// gopsutil for the calling process.
+func NewGopsutilSource() (MetricsSource, error) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	return &gopsutilSource{proc: proc}, nil
+}
+
+func (s *gopsutilSource) Sample() (Sample, error) {
+	var sample Sample
+
+	memInfo, err := s.proc.MemoryInfo()
+	if err != nil {
+		return Sample{}, err
+	}
+	sample.ProcRSSMB = float64(memInfo.RSS) / (1024 * 1024)
+
+	cpuPercents, err := cpu.Percent(0, false)
+	if err != nil {
+		return Sample{}, err
+	}
+	if len(cpuPercents) > 0 {
+		sample.SysCPUPercent = cpuPercents[0]
+	}
+
+	loadAvg, err := load.Avg()
+	if err != nil {
+		return Sample{}, err
+	}
+	sample.LoadAvg1 = loadAvg.Load1
+
+	virtMem, err := mem.VirtualMemory()
+	if err != nil {
+		return Sample{}, err
+	}
+	sample.AvailableMB = float64(virtMem.Available) / (1024 * 1024)
+
+	return sample, nil
+}
@@ -0,0 +1,30 @@
+// Package sysmetrics samples host-level resource usage (process RSS,
+// system CPU, load average, available memory) so it can be joined
+// against a gctrace stream by timestamp. The sampling mechanism is
+// behind the MetricsSource interface so environments that can't use
+// gopsutil directly (e.g. inside a container) can substitute a cgroup
+// v2 reader without touching the join logic.
+package sysmetrics
+
+// Sample is one point-in-time reading of host resource usage.
+type Sample struct {
+	// Elapsed is seconds since the Collector started. This is memgc's
+	// own clock, not the traced program's: Collector.Nearest calibrates
+	// the offset between the two the first time it's called, so samples
+	// can still be joined against GCTrace.Timestamp even when memgc
+	// attaches to an already-running program.
+	Elapsed float64
+
+	ProcRSSMB     float64
+	SysCPUPercent float64
+	LoadAvg1      float64
+	AvailableMB   float64
+}
+
+// MetricsSource produces one Sample per call. Implementations are
+// expected to be relatively cheap, since a Collector calls Sample on a
+// timer, but any I/O (reading /proc, cgroup files, etc.) happens here
+// rather than in the hot gctrace parsing loop.
+type MetricsSource interface {
+	Sample() (Sample, error)
+}
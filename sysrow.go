@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/KunalSin9h/memgc/sysmetrics"
+)
+
+// systemMetricsHeader is the set of columns --with-system-metrics adds
+// to the CSV, joined onto each GCTrace row by nearest timestamp.
+var systemMetricsHeader = []string{"ProcRSSMB", "SysCPUPercent", "LoadAvg1", "AvailableMB"}
+
+func systemMetricsRow(s sysmetrics.Sample) []string {
+	return []string{
+		strconv.FormatFloat(s.ProcRSSMB, 'f', -1, 64),
+		strconv.FormatFloat(s.SysCPUPercent, 'f', -1, 64),
+		strconv.FormatFloat(s.LoadAvg1, 'f', -1, 64),
+		strconv.FormatFloat(s.AvailableMB, 'f', -1, 64),
+	}
+}
+
+// gcTraceWithSystemMetrics decorates a GCTrace with its nearest-timestamp
+// system-metrics sample so the joined row still satisfies csvRecord.
+type gcTraceWithSystemMetrics struct {
+	trace *GCTrace
+	sys   sysmetrics.Sample
+}
+
+func (g gcTraceWithSystemMetrics) ToCSVHeader() []string {
+	return append(g.trace.ToCSVHeader(), systemMetricsHeader...)
+}
+
+func (g gcTraceWithSystemMetrics) ToCSVRow() []string {
+	return append(g.trace.ToCSVRow(), systemMetricsRow(g.sys)...)
+}